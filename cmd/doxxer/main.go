@@ -27,11 +27,17 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"encoding/json"
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
 
+	"github.com/sstallion/go-tools/command"
 	"github.com/sstallion/go-tools/generate"
 	"github.com/sstallion/go-tools/util"
 )
@@ -47,7 +53,10 @@ const tmplText = `
 package {{ .Package }}
 `
 
-var output string
+var (
+	output string
+	format string
+)
 
 func usage() {
 	util.PrintGlobalUsage(`
@@ -55,24 +64,35 @@ Doxxer is a tool that generates documentation for command line applications.
 
 Usage:
 
-  {{ .Program }} [-o output] <package> [arguments...]
+  {{ .Program }} [-o output] [-format format] <package> [arguments...]
 
 Flags:
 
   {{ call .PrintDefaults }}
 
 Typically, arguments are specified using "//go:generate" directives, which are
-passed verbatim to "go run" to generate output. Output is then passed through
-"gofmt" and finally written to a file, which by default is doc.go.
+passed verbatim to "go run" to generate output.
 
-The following example demonstrates generating documentation for an application
-that makes use of the standard flag package:
+In "godoc" mode (the default, kept for backward compatibility), arguments are
+passed verbatim to "go run", output is passed through "gofmt", and the result
+is written to a file, which by default is doc.go:
 
 Example:
 
   //go:generate doxxer . -h
   package main
 
+In "md", "man", and "txt" modes, arguments after <package> are ignored.
+Instead, doxxer runs the package's hidden "__dump" command, which walks its
+command.CommandSet (see command.Walk) and reports a structured description of
+its command tree, and renders:
+
+  md    a directory of Markdown files, one per command, plus an index.md
+        linking to each; the default output is "docs"
+  man   groff man pages, one per command, written to
+        <output>/man1/<program>-<subcommand...>.1; the default output is "man"
+  txt   a single plain text reference; the default output is "doc.txt"
+
 If doxxer is called directly from the command line, the $GOROOT and $GOPACKAGE
 environment variables must be defined as documented in "go help generate".
 
@@ -92,7 +112,8 @@ func main() {
 	log.SetPrefix("doxxer: ")
 
 	flag.Usage = usage
-	flag.StringVar(&output, "o", "doc.go", "Write `output` to file")
+	flag.StringVar(&output, "o", "", "Write `output` to file or directory, depending on format")
+	flag.StringVar(&format, "format", "godoc", "Generate documentation in `format` (godoc, md, man, or txt)")
 	flag.Parse()
 
 	args := flag.Args()
@@ -101,6 +122,23 @@ func main() {
 		os.Exit(1)
 	}
 
+	switch format {
+	case "godoc":
+		runGodoc(args)
+	case "md", "man", "txt":
+		runDump(args[0])
+	default:
+		log.Fatalf("unsupported format: %s", format)
+	}
+}
+
+// runGodoc implements the original doxxer behavior: args are passed verbatim
+// to "go run", and its captured output is wrapped in a doc.go comment.
+func runGodoc(args []string) {
+	if output == "" {
+		output = "doc.go"
+	}
+
 	var in bytes.Buffer
 	cmd := generate.GoRunCmd(args[0], args[1:])
 	cmd.Stdout = &in
@@ -128,4 +166,140 @@ func main() {
 	if err := generate.WriteSource(output, out.Bytes(), 0666); err != nil {
 		log.Fatal(err)
 	}
-}
\ No newline at end of file
+}
+
+// runDump runs pkg's hidden "__dump" command to obtain a structured
+// description of its command tree, then renders it in the requested format.
+func runDump(pkg string) {
+	var out bytes.Buffer
+	cmd := generate.GoRunCmd(pkg, []string{"__dump"})
+	cmd.Stdout = &out
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		log.Fatal(err)
+	}
+
+	var dump command.Dump
+	if err := json.Unmarshal(out.Bytes(), &dump); err != nil {
+		log.Fatal(err)
+	}
+	sort.Slice(dump.Commands, func(i, j int) bool {
+		return strings.Join(dump.Commands[i].Path, " ") < strings.Join(dump.Commands[j].Path, " ")
+	})
+
+	var err error
+	switch format {
+	case "md":
+		if output == "" {
+			output = "docs"
+		}
+		err = writeMarkdown(dump, output)
+	case "man":
+		if output == "" {
+			output = "man"
+		}
+		err = writeMan(dump, output)
+	case "txt":
+		if output == "" {
+			output = "doc.txt"
+		}
+		err = writeText(dump, output)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+// writeMarkdown renders dump as a directory of files suitable for a docs
+// site: an index.md linking to one file per command.
+func writeMarkdown(dump command.Dump, dir string) error {
+	if err := os.MkdirAll(dir, 0777); err != nil {
+		return err
+	}
+
+	var index strings.Builder
+	fmt.Fprintf(&index, "# %s\n\n", dump.Program)
+	fmt.Fprintln(&index, "## Commands")
+	fmt.Fprintln(&index)
+
+	used := map[string]bool{"index.md": true}
+	for _, node := range dump.Commands {
+		name := strings.Join(node.Path, " ")
+		file := strings.Join(node.Path, "_") + ".md"
+		if used[file] {
+			return fmt.Errorf("command file name collision: %s", file)
+		}
+		used[file] = true
+		fmt.Fprintf(&index, "- [%s](%s) - %s\n", name, file, node.Description)
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "# %s\n\n", name)
+		if node.Description != "" {
+			fmt.Fprintf(&b, "%s\n\n", node.Description)
+		}
+		if len(node.Flags) > 0 {
+			fmt.Fprintln(&b, "## Flags")
+			fmt.Fprintln(&b)
+			fmt.Fprintln(&b, "| Name | Default | Description |")
+			fmt.Fprintln(&b, "| --- | --- | --- |")
+			for _, f := range node.Flags {
+				fmt.Fprintf(&b, "| `-%s` | `%s` | %s |\n", f.Name, f.Default, f.Usage)
+			}
+		}
+		if err := generate.WriteFile(filepath.Join(dir, file), []byte(b.String()), 0666); err != nil {
+			return err
+		}
+	}
+	return generate.WriteFile(filepath.Join(dir, "index.md"), []byte(index.String()), 0666)
+}
+
+// writeMan renders dump as groff man pages, one per command, written to
+// <dir>/man1/<program>-<subcommand...>.1.
+func writeMan(dump command.Dump, dir string) error {
+	man1 := filepath.Join(dir, "man1")
+	if err := os.MkdirAll(man1, 0777); err != nil {
+		return err
+	}
+
+	used := make(map[string]bool)
+	for _, node := range dump.Commands {
+		name := dump.Program + "-" + strings.Join(node.Path, "-")
+		if used[name] {
+			return fmt.Errorf("man page name collision: %s", name)
+		}
+		used[name] = true
+		file := filepath.Join(man1, name+".1")
+
+		var b strings.Builder
+		fmt.Fprintf(&b, ".TH %s 1\n", strings.ToUpper(name))
+		fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", name, node.Description)
+		fmt.Fprintf(&b, ".SH SYNOPSIS\n.B %s %s\n", dump.Program, strings.Join(node.Path, " "))
+		if len(node.Flags) > 0 {
+			fmt.Fprintln(&b, ".SH OPTIONS")
+			for _, f := range node.Flags {
+				fmt.Fprintf(&b, ".TP\n.B \\-%s\n%s (default %q)\n", f.Name, f.Usage, f.Default)
+			}
+		}
+		if err := generate.WriteFile(file, []byte(b.String()), 0666); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeText renders dump as a single plain text reference.
+func writeText(dump command.Dump, output string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s\n\n", dump.Program)
+	for _, node := range dump.Commands {
+		fmt.Fprintf(&b, "%s %s\n", dump.Program, strings.Join(node.Path, " "))
+		if node.Description != "" {
+			fmt.Fprintf(&b, "    %s\n", node.Description)
+		}
+		for _, f := range node.Flags {
+			fmt.Fprintf(&b, "    -%s\n        %s (default %q)\n", f.Name, f.Usage, f.Default)
+		}
+		fmt.Fprintln(&b)
+	}
+	return generate.WriteFile(output, []byte(b.String()), 0666)
+}
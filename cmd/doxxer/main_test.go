@@ -0,0 +1,132 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sstallion/go-tools/command"
+)
+
+func testDump() command.Dump {
+	return command.Dump{
+		Program: "tool",
+		Commands: []command.DumpNode{
+			{
+				Path:        []string{"add"},
+				Description: "Add a thing",
+				Flags: []command.FlagInfo{
+					{Name: "tag", Usage: "tag to apply", Default: ""},
+				},
+			},
+			{
+				Path:        []string{"remote", "add"},
+				Description: "Add a remote",
+			},
+		},
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeMarkdown(testDump(), dir); err != nil {
+		t.Fatalf("writeMarkdown() = %v, want nil", err)
+	}
+
+	index, err := os.ReadFile(filepath.Join(dir, "index.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(index), "add.md") || !strings.Contains(string(index), "remote_add.md") {
+		t.Fatalf("index.md = %q, want links to add.md and remote_add.md", index)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "add.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), "-tag") {
+		t.Fatalf("add.md = %q, want it to document -tag", page)
+	}
+}
+
+func TestWriteMarkdownCollision(t *testing.T) {
+	dump := command.Dump{
+		Program: "tool",
+		Commands: []command.DumpNode{
+			{Path: []string{"remote", "add"}},
+			{Path: []string{"remote_add"}}, // collides with the file name above
+		},
+	}
+	if err := writeMarkdown(dump, t.TempDir()); err == nil {
+		t.Fatal("writeMarkdown() = nil error, want a collision error")
+	}
+}
+
+func TestWriteMan(t *testing.T) {
+	dir := t.TempDir()
+	if err := writeMan(testDump(), dir); err != nil {
+		t.Fatalf("writeMan() = %v, want nil", err)
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "man1", "tool-add.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(page), ".TH TOOL-ADD 1") {
+		t.Fatalf("tool-add.1 = %q, want a .TH header", page)
+	}
+}
+
+func TestWriteManCollision(t *testing.T) {
+	dump := command.Dump{
+		Program: "tool",
+		Commands: []command.DumpNode{
+			{Path: []string{"remote", "add"}},
+			{Path: []string{"remote-add"}}, // collides with "tool-remote-add"
+		},
+	}
+	if err := writeMan(dump, t.TempDir()); err == nil {
+		t.Fatal("writeMan() = nil error, want a collision error")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "doc.txt")
+	if err := writeText(testDump(), output); err != nil {
+		t.Fatalf("writeText() = %v, want nil", err)
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := string(data)
+	if !strings.Contains(text, "tool add") || !strings.Contains(text, "tool remote add") {
+		t.Fatalf("doc.txt = %q, want entries for both commands", text)
+	}
+}
@@ -48,9 +48,57 @@
 // Finally, after all commands have been defined, call:
 //
 //	command.Parse()
+//
+// # Subcommands
+//
+// A command may own a nested CommandSet of its own, allowing tools to expose
+// a multi-level command tree (e.g. "mytool remote add ..."), by additionally
+// satisfying the Parent interface. When CommandSet.Parse matches a command
+// implementing Parent, any arguments left over once the command's own flags
+// have been parsed are dispatched to the matching child, and so on for
+// further levels of nesting.
+//
+// Commands that need to share setup or teardown logic with their children
+// may implement the optional PreRunner and PostRunner interfaces; both are
+// honored for every command along the matched chain, outermost first for
+// PreRun and innermost first for PostRun.
+//
+// # Logging
+//
+// Parse registers a -v/--log-level global flag and logs the name, arguments,
+// duration, and exit code of every command it runs using a *slog.Logger.
+// Commands that implement ContextRunner are called with a context.Context
+// carrying a logger annotated with their name and arguments, retrievable
+// with LoggerFromContext; commands that only implement Run are unaffected.
+// SetLogger may be called before Parse to plug in a different slog.Handler.
+//
+// # Required flags
+//
+// Flags that must be set may be registered with RequireFlag. If such a flag
+// was not explicitly set once a command's flags have been parsed, CommandSet
+// prompts for a value on a TTY using the util/prompt Ui set with SetUi, or
+// fails with an ErrNArg-wrapping error otherwise.
+//
+// # Config files and environment variables
+//
+// Calling BindConfig on a command's flag set registers a -config flag and
+// causes any flag left unset on the command line to fall back to an
+// environment variable, then to a value read from the file named by
+// -config, before finally falling back to the flag's declared default.
+// PrintResolvedConfig can be referenced from a usage template to show the
+// source used for each flag, which is useful when debugging tools that mix
+// flags, environment variables, and config files.
+//
+// # Introspection
+//
+// Walk visits every command in a command tree, including nested
+// subcommands, without requiring the caller to know its shape ahead of
+// time; it is the basis for the "__dump" hidden command and is available
+// for other tools, such as cmd/doxxer, to build on.
 package command
 
 import (
+	"context"
 	"errors"
 	"flag"
 	"fmt"
@@ -58,6 +106,7 @@ import (
 	"strings"
 	"text/tabwriter"
 	"text/template"
+	"time"
 
 	"github.com/sstallion/go-tools/util"
 )
@@ -82,6 +131,42 @@ type Command interface {
 	Run() error
 }
 
+// Flagger is an optional interface implemented by commands that expose the
+// *flag.FlagSet used to parse their own flags, allowing it to be introspected
+// by callers outside the command itself.
+type Flagger interface {
+	Flags() *flag.FlagSet
+}
+
+// Parent is an optional interface implemented by commands that own a nested
+// CommandSet, allowing them to act as the root of a subcommand tree (e.g.
+// "remote" in "git remote add"). Flags returns the *flag.FlagSet used by
+// Parse to parse the command's own flags; CommandSet uses it to obtain any
+// arguments remaining once those flags have been parsed so they may be
+// dispatched to a child command. Commands returns the nested CommandSet used
+// to resolve those children.
+type Parent interface {
+	Command
+	Flagger
+	Commands() *CommandSet
+}
+
+// PreRunner is an optional interface implemented by commands that perform
+// setup prior to Run. If a command along a matched subcommand chain
+// implements PreRunner, PreRun is called before Run, outermost command
+// first, allowing a parent to share setup with its children.
+type PreRunner interface {
+	PreRun() error
+}
+
+// PostRunner is an optional interface implemented by commands that perform
+// teardown after Run. If a command along a matched subcommand chain
+// implements PostRunner, PostRun is called after Run, innermost command
+// first.
+type PostRunner interface {
+	PostRun() error
+}
+
 // CommandSet describes a set of defined commands.
 type CommandSet []Command
 
@@ -118,20 +203,28 @@ func (cmds *CommandSet) Parse(flags *flag.FlagSet, arguments []string) {
 	flags.Parse(arguments)
 
 	args := flags.Args()
+	if len(args) > 0 && args[0] == completeCommandName {
+		for _, word := range cmds.Complete(args[1:]) {
+			fmt.Println(word)
+		}
+		os.Exit(0)
+	}
+	if len(args) > 0 && args[0] == dumpCommandName {
+		if err := cmds.writeDump(os.Stdout); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+		os.Exit(0)
+	}
+	cmds.dispatch(context.Background(), flags, args)
+}
+
+// dispatch locates the command named by args[0] in cmds, if any, and runs
+// it, falling back to flags.Usage if no match is found.
+func (cmds *CommandSet) dispatch(ctx context.Context, flags *flag.FlagSet, args []string) {
 	if len(args) > 0 {
-		for _, cmd := range *cmds {
-			if cmd.Name() != args[0] {
-				continue
-			}
-			if err := cmd.Parse(args[1:]); err != nil {
-				fmt.Fprintln(os.Stderr, err)
-				cmd.Usage()
-				os.Exit(1)
-			}
-			if err := cmd.Run(); err != nil {
-				fmt.Fprintln(os.Stderr, err)
-				os.Exit(1)
-			}
+		if cmd := cmds.Lookup(args[0]); cmd != nil {
+			cmds.run(ctx, cmd, args[1:])
 			return
 		}
 		fmt.Fprintf(os.Stderr, "invalid command: %s\n", args[0])
@@ -140,6 +233,87 @@ func (cmds *CommandSet) Parse(flags *flag.FlagSet, arguments []string) {
 	os.Exit(1)
 }
 
+// run parses arguments for cmd and runs it, recursing into cmd's own
+// CommandSet if cmd implements Parent and arguments remain once cmd's flags
+// have been parsed. PreRunner and PostRunner are honored for cmd and, when
+// recursing, for every command along the matched chain. ctx is annotated
+// with a logger scoped to cmd (see LoggerFromContext) and passed to cmd's
+// RunContext method, if implemented.
+func (cmds *CommandSet) run(ctx context.Context, cmd Command, arguments []string) {
+	if err := cmd.Parse(arguments); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		cmd.Usage()
+		os.Exit(1)
+	}
+
+	if flagger, ok := cmd.(Flagger); ok {
+		if err := resolveConfig(flagger.Flags()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			cmd.Usage()
+			os.Exit(1)
+		}
+		if err := resolveRequiredFlags(flagger.Flags()); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			cmd.Usage()
+			os.Exit(1)
+		}
+	}
+
+	logger := LoggerFromContext(ctx).With("command", cmd.Name(), "args", arguments)
+	ctx = context.WithValue(ctx, loggerKey{}, logger)
+
+	if parent, ok := cmd.(Parent); ok {
+		if args := parent.Flags().Args(); len(args) > 0 {
+			child := parent.Commands().Lookup(args[0])
+			if child == nil {
+				fmt.Fprintf(os.Stderr, "invalid command: %s\n", args[0])
+				cmd.Usage()
+				os.Exit(1)
+			}
+			preRun(cmd)
+			parent.Commands().run(ctx, child, args[1:])
+			postRun(cmd)
+			return
+		}
+	}
+
+	preRun(cmd)
+	start := time.Now()
+	err := runCommand(ctx, cmd)
+	exitCode := 0
+	if err != nil {
+		exitCode = 1
+	}
+	logger.Debug("command finished", "duration", time.Since(start), "exit_code", exitCode)
+	postRun(cmd)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// preRun calls PreRun if cmd implements PreRunner, calling os.Exit with an
+// appropriate error code on failure.
+func preRun(cmd Command) {
+	if pre, ok := cmd.(PreRunner); ok {
+		if err := pre.PreRun(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
+// postRun calls PostRun if cmd implements PostRunner, calling os.Exit with an
+// appropriate error code on failure.
+func postRun(cmd Command) {
+	if post, ok := cmd.(PostRunner); ok {
+		if err := post.PostRun(); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(1)
+		}
+	}
+}
+
 // PrintUsage prints a help message to standard error. Usage is typically a
 // template string that can reference the following variables and functions:
 //
@@ -158,6 +332,10 @@ func (cmds *CommandSet) Parse(flags *flag.FlagSet, arguments []string) {
 //	PrintCommands
 //		PrintCommands prints to standard error the names and
 //		descriptions of all defined commands in the command set.
+//	PrintResolvedConfig
+//		PrintResolvedConfig prints to standard error the value and
+//		source (flag, environment variable, config file, or default)
+//		of every flag in the flag set bound with BindConfig.
 func (cmds *CommandSet) PrintUsage(flags *flag.FlagSet, usage string) {
 	usage = strings.TrimSpace(usage) + "\n"
 	t := template.Must(template.New("").Parse(usage))
@@ -173,16 +351,51 @@ func (cmds *CommandSet) PrintUsage(flags *flag.FlagSet, usage string) {
 		"PrintCommands": func() string {
 			var b strings.Builder
 			w := tabwriter.NewWriter(&b, 2*8, 0, 0, ' ', 0)
-			cmds.Visit(func(cmd Command) {
-				if desc := cmd.Description(); desc != "" {
-					fmt.Fprintf(w, "  %s\t%s\f", cmd.Name(), desc)
+			writeCommands(w, cmds, 0)
+			return strings.TrimSpace(b.String())
+		},
+		"PrintResolvedConfig": func() string {
+			var b strings.Builder
+			w := tabwriter.NewWriter(&b, 2*8, 0, 0, ' ', 0)
+			set := make(map[string]bool)
+			flags.Visit(func(f *flag.Flag) { set[f.Name] = true })
+			configMu.Lock()
+			sources := configSources[flags]
+			configMu.Unlock()
+			flags.VisitAll(func(f *flag.Flag) {
+				// resolveConfig sets env and config file values with
+				// fs.Set, so they also appear in set; check sources first.
+				source := sources[f.Name]
+				switch {
+				case source != "":
+				case set[f.Name]:
+					source = "flag"
+				default:
+					source = "default"
 				}
+				fmt.Fprintf(w, "  %s\t%s\t(%s)\f", f.Name, f.Value.String(), source)
 			})
+			w.Flush()
 			return strings.TrimSpace(b.String())
 		},
 	})
 }
 
+// writeCommands writes the names and descriptions of all commands in cmds to
+// w, recursing into nested CommandSets owned by commands implementing Parent
+// and indenting each level of the tree.
+func writeCommands(w *tabwriter.Writer, cmds *CommandSet, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	cmds.Visit(func(cmd Command) {
+		if desc := cmd.Description(); desc != "" {
+			fmt.Fprintf(w, "%s%s\t%s\f", indent, cmd.Name(), desc)
+		}
+		if parent, ok := cmd.(Parent); ok {
+			writeCommands(w, parent.Commands(), depth+1)
+		}
+	})
+}
+
 // CommandLine is the default set of commands, parsed from os.Arg.
 var CommandLine CommandSet
 
@@ -205,6 +418,7 @@ func Visit(fn func(Command)) {
 
 // Parse parses the command line flags and commands from os.Args[1:].
 func Parse() {
+	RegisterLogFlags(flag.CommandLine)
 	CommandLine.Parse(flag.CommandLine, os.Args[1:])
 }
 
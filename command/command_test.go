@@ -0,0 +1,202 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type fakeCmd struct {
+	name string
+	desc string
+}
+
+func (c *fakeCmd) Name() string         { return c.name }
+func (c *fakeCmd) Description() string  { return c.desc }
+func (c *fakeCmd) Usage()               {}
+func (c *fakeCmd) Parse([]string) error { return nil }
+func (c *fakeCmd) Run() error           { return nil }
+
+func TestCommandSetAddLookupVisit(t *testing.T) {
+	var cmds CommandSet
+	cmds.Add(&fakeCmd{name: "foo"})
+	cmds.Add(&fakeCmd{name: "bar"})
+	cmds.Add(&fakeCmd{name: "foo"}) // duplicate, ignored
+
+	if got := cmds.Lookup("foo"); got == nil || got.Name() != "foo" {
+		t.Fatalf("Lookup(%q) = %v, want the first foo command", "foo", got)
+	}
+	if got := cmds.Lookup("missing"); got != nil {
+		t.Fatalf("Lookup(%q) = %v, want nil", "missing", got)
+	}
+
+	var names []string
+	cmds.Visit(func(cmd Command) { names = append(names, cmd.Name()) })
+	if want := []string{"foo", "bar"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Visit order = %v, want %v", names, want)
+	}
+}
+
+type preTrackingCmd struct {
+	fakeCmd
+	ran *[]string
+}
+
+func (c *preTrackingCmd) PreRun() error {
+	*c.ran = append(*c.ran, c.name)
+	return nil
+}
+
+func TestPreRunCallsPreRunnerOnly(t *testing.T) {
+	var ran []string
+	preRun(&preTrackingCmd{fakeCmd: fakeCmd{name: "has-prerun"}, ran: &ran})
+	preRun(&fakeCmd{name: "no-prerun"}) // must not panic
+
+	if want := []string{"has-prerun"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("ran = %v, want %v", ran, want)
+	}
+}
+
+type postTrackingCmd struct {
+	fakeCmd
+	err error
+	ran *[]string
+}
+
+func (c *postTrackingCmd) PostRun() error {
+	*c.ran = append(*c.ran, c.name)
+	return c.err
+}
+
+func TestPostRunCallsPostRunnerOnly(t *testing.T) {
+	var ran []string
+	postRun(&postTrackingCmd{fakeCmd: fakeCmd{name: "has-postrun"}, ran: &ran})
+	postRun(&fakeCmd{name: "no-postrun"}) // must not panic
+
+	if want := []string{"has-postrun"}; !reflect.DeepEqual(ran, want) {
+		t.Fatalf("ran = %v, want %v", ran, want)
+	}
+}
+
+// failingCmd is a leaf command whose Run fails and whose PostRun prints a
+// sentinel, used by TestPostRunRunsWhenCommandFails to check that run
+// invokes PostRun regardless of Run's result.
+type failingCmd struct{ fakeCmd }
+
+func (c *failingCmd) Run() error { return errors.New("boom") }
+func (c *failingCmd) PostRun() error {
+	fmt.Println("postrun-ran")
+	return nil
+}
+
+// orderTrackingCmd is a leaf command used by TestParentDispatchRunsChildInOrder
+// that records its own name in *order when PreRun, Run, and PostRun fire.
+type orderTrackingCmd struct {
+	fakeCmd
+	flags *flag.FlagSet
+	order *[]string
+}
+
+func (c *orderTrackingCmd) Flags() *flag.FlagSet      { return c.flags }
+func (c *orderTrackingCmd) Parse(args []string) error { return c.flags.Parse(args) }
+func (c *orderTrackingCmd) PreRun() error             { *c.order = append(*c.order, c.name+":prerun"); return nil }
+func (c *orderTrackingCmd) PostRun() error {
+	*c.order = append(*c.order, c.name+":postrun")
+	return nil
+}
+func (c *orderTrackingCmd) Run() error { *c.order = append(*c.order, c.name+":run"); return nil }
+
+// orderTrackingParentCmd is an orderTrackingCmd that additionally owns a
+// nested CommandSet, making it a Parent.
+type orderTrackingParentCmd struct {
+	orderTrackingCmd
+	sub CommandSet
+}
+
+func (c *orderTrackingParentCmd) Commands() *CommandSet { return &c.sub }
+
+// TestParentDispatchRunsChildInOrder dispatches through CommandSet.Parse and
+// run with a two-level Parent/child chain, and checks that the child
+// actually ran and that PreRunner/PostRunner fired in the documented order:
+// outermost first for PreRun, innermost first for PostRun.
+func TestParentDispatchRunsChildInOrder(t *testing.T) {
+	var order []string
+
+	child := &orderTrackingCmd{
+		fakeCmd: fakeCmd{name: "child", desc: "Child command"},
+		flags:   flag.NewFlagSet("child", flag.ContinueOnError),
+		order:   &order,
+	}
+	parent := &orderTrackingParentCmd{
+		orderTrackingCmd: orderTrackingCmd{
+			fakeCmd: fakeCmd{name: "parent", desc: "Parent command"},
+			flags:   flag.NewFlagSet("parent", flag.ContinueOnError),
+			order:   &order,
+		},
+	}
+	parent.sub.Add(child)
+
+	var cmds CommandSet
+	cmds.Add(parent)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cmds.Parse(fs, []string{"parent", "child"})
+
+	want := []string{"parent:prerun", "child:prerun", "child:run", "child:postrun", "parent:postrun"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("order = %v, want %v", order, want)
+	}
+}
+
+// TestPostRunRunsWhenCommandFails re-invokes the test binary as a subprocess
+// so it can observe run's os.Exit(1) on a failing command without exiting
+// the test process itself. It guards against a regression where PostRun was
+// skipped whenever the command's Run returned an error.
+func TestPostRunRunsWhenCommandFails(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") == "1" {
+		var cmds CommandSet
+		cmds.run(context.Background(), &failingCmd{fakeCmd{name: "fails"}}, nil)
+		return
+	}
+
+	cmd := exec.Command(os.Args[0], "-test.run=TestPostRunRunsWhenCommandFails")
+	cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1")
+	out, err := cmd.CombinedOutput()
+
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+		t.Fatalf("subprocess exited with %v, want exit code 1; output: %s", err, out)
+	}
+	if !strings.Contains(string(out), "postrun-ran") {
+		t.Fatalf("subprocess output = %q, want it to contain %q", out, "postrun-ran")
+	}
+}
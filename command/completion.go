@@ -0,0 +1,296 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/sstallion/go-tools/util"
+)
+
+// completeCommandName is the hidden command used by generated completion
+// scripts to request a list of completions from a running binary. It is
+// handled directly by CommandSet.Parse and never shown in usage output.
+const completeCommandName = "__complete"
+
+// Completer is an optional interface implemented by commands that supply
+// dynamic completions for their positional arguments, such as file names or
+// values looked up at runtime. Complete is called with the arguments already
+// present on the command line after the command's name (excluding flags) and
+// the partial word being completed, and returns the list of valid completions.
+type Completer interface {
+	Complete(args []string, toComplete string) []string
+}
+
+// Complete returns the list of valid completions for args using the default
+// command set, where args holds the words following the program name, the
+// last of which may be a partial word to complete. See CommandSet.Complete
+// for details.
+func Complete(args []string) []string {
+	return CommandLine.Complete(args)
+}
+
+// Complete returns the list of valid completions for args, where args holds
+// the words following the program name, the last of which may be a partial
+// word to complete. Candidates include the names of commands and nested
+// subcommands, their flags, and, for commands implementing Completer, any
+// dynamic completions they supply.
+func (cmds *CommandSet) Complete(args []string) []string {
+	toComplete := ""
+	if len(args) > 0 {
+		toComplete = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+
+	cur := cmds
+	var cmd Command
+	var cmdArgs []string
+	for i, arg := range args {
+		if strings.HasPrefix(arg, "-") {
+			continue
+		}
+		next := cur.Lookup(arg)
+		if next == nil {
+			for _, rest := range args[i:] {
+				if !strings.HasPrefix(rest, "-") {
+					cmdArgs = append(cmdArgs, rest)
+				}
+			}
+			break
+		}
+		cmd = next
+		if parent, ok := next.(Parent); ok {
+			cur = parent.Commands()
+		} else {
+			cur = &CommandSet{}
+		}
+	}
+
+	var words []string
+	if cmd != nil {
+		if completer, ok := cmd.(Completer); ok {
+			words = append(words, completer.Complete(cmdArgs, toComplete)...)
+		}
+		if flagger, ok := cmd.(Flagger); ok {
+			flagger.Flags().VisitAll(func(f *flag.Flag) {
+				words = append(words, "--"+f.Name)
+			})
+		}
+	}
+	cur.Visit(func(c Command) {
+		if c.Description() != "" {
+			words = append(words, c.Name())
+		}
+	})
+
+	var matches []string
+	for _, word := range words {
+		if strings.HasPrefix(word, toComplete) {
+			matches = append(matches, word)
+		}
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// GenerateCompletion writes a shell completion script for the default
+// command set to w. Supported values of shell are "bash", "zsh", "fish", and
+// "powershell".
+func GenerateCompletion(shell string, w io.Writer) error {
+	return CommandLine.GenerateCompletion(shell, w)
+}
+
+// GenerateCompletion writes a shell completion script for cmds to w.
+// Supported values of shell are "bash", "zsh", "fish", and "powershell".
+// Generated scripts resolve completions by re-invoking the program with the
+// hidden "__complete" command, so they stay in sync with the command set
+// without needing to be regenerated when commands, flags, or Completer
+// implementations change.
+func (cmds *CommandSet) GenerateCompletion(shell string, w io.Writer) error {
+	program := util.Program()
+	switch shell {
+	case "bash":
+		return writeBashCompletion(w, program)
+	case "zsh":
+		return writeZshCompletion(w, program)
+	case "fish":
+		return writeFishCompletion(w, program)
+	case "powershell":
+		return writePowerShellCompletion(w, program)
+	default:
+		return fmt.Errorf("unsupported shell: %s", shell)
+	}
+}
+
+func writeBashCompletion(w io.Writer, program string) error {
+	_, err := fmt.Fprintf(w, `# bash completion for %[1]s
+# Code generated by "command.GenerateCompletion"; DO NOT EDIT.
+
+_%[1]s_completions() {
+	local cur words
+	cur="${COMP_WORDS[COMP_CWORD]}"
+	words=$(%[1]s %[2]s "${COMP_WORDS[@]:1:COMP_CWORD-1}" "$cur" 2>/dev/null)
+	COMPREPLY=($(compgen -W "$words" -- "$cur"))
+}
+
+complete -F _%[1]s_completions %[1]s
+`, program, completeCommandName)
+	return err
+}
+
+func writeZshCompletion(w io.Writer, program string) error {
+	_, err := fmt.Fprintf(w, `#compdef %[1]s
+# zsh completion for %[1]s
+# Code generated by "command.GenerateCompletion"; DO NOT EDIT.
+
+_%[1]s() {
+	local cur words
+	cur="${words[CURRENT]}"
+	local -a completions
+	completions=("${(@f)$(%[1]s %[2]s "${words[@]:1:CURRENT-2}" "$cur" 2>/dev/null)}")
+	compadd -- "${completions[@]}"
+}
+
+compdef _%[1]s %[1]s
+`, program, completeCommandName)
+	return err
+}
+
+func writeFishCompletion(w io.Writer, program string) error {
+	_, err := fmt.Fprintf(w, `# fish completion for %[1]s
+# Code generated by "command.GenerateCompletion"; DO NOT EDIT.
+
+function __%[1]s_complete
+	set -l cmd (commandline -opc)
+	set -l cur (commandline -ct)
+	%[1]s %[2]s $cmd[2..-1] $cur
+end
+
+complete -c %[1]s -f -a '(__%[1]s_complete)'
+`, program, completeCommandName)
+	return err
+}
+
+func writePowerShellCompletion(w io.Writer, program string) error {
+	_, err := fmt.Fprintf(w, `# PowerShell completion for %[1]s
+# Code generated by "command.GenerateCompletion"; DO NOT EDIT.
+
+Register-ArgumentCompleter -Native -CommandName %[1]s -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+
+	$words = $commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() }
+	& %[1]s %[2]s @words $wordToComplete | ForEach-Object {
+		[System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+	}
+}
+`, program, completeCommandName)
+	return err
+}
+
+// completionCmd implements a reusable "completion" command that generates
+// shell completion scripts for another CommandSet, exposing one subcommand
+// per supported shell via CommandSet nesting (see the Parent interface).
+type completionCmd struct {
+	cmds  *CommandSet
+	flags *flag.FlagSet
+	sub   CommandSet
+}
+
+// NewCompletionCommand returns a Command that generates shell completion
+// scripts for cmds. The returned command is typically registered under the
+// name "completion" with a call to cmds.Add:
+//
+//	cmds.Add(command.NewCompletionCommand(cmds))
+func NewCompletionCommand(cmds *CommandSet) Command {
+	cmd := &completionCmd{
+		cmds:  cmds,
+		flags: flag.NewFlagSet("completion", flag.ExitOnError),
+	}
+	cmd.flags.Usage = cmd.Usage
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		cmd.sub.Add(&completionShellCmd{cmds: cmds, shell: shell})
+	}
+	return cmd
+}
+
+func (cmd *completionCmd) Name() string        { return "completion" }
+func (cmd *completionCmd) Description() string { return "Generate shell completion scripts" }
+
+func (cmd *completionCmd) Usage() {
+	cmd.sub.PrintUsage(cmd.flags, `
+Usage:
+
+  {{ .Program }} completion <shell>
+
+Generates a shell completion script to standard output. Supported shells:
+
+{{ call .PrintCommands }}
+`)
+}
+
+func (cmd *completionCmd) Parse(arguments []string) error { return cmd.flags.Parse(arguments) }
+func (cmd *completionCmd) Flags() *flag.FlagSet           { return cmd.flags }
+func (cmd *completionCmd) Commands() *CommandSet          { return &cmd.sub }
+
+func (cmd *completionCmd) Run() error {
+	cmd.Usage()
+	return ErrNArg
+}
+
+// completionShellCmd generates a completion script for a single shell; it is
+// registered as a child of completionCmd.
+type completionShellCmd struct {
+	cmds  *CommandSet
+	shell string
+}
+
+func (cmd *completionShellCmd) Name() string { return cmd.shell }
+
+func (cmd *completionShellCmd) Description() string {
+	return fmt.Sprintf("Generate a %s completion script", cmd.shell)
+}
+
+func (cmd *completionShellCmd) Usage() {
+	util.PrintGlobalUsage(fmt.Sprintf(`
+Usage:
+
+  {{ .Program }} completion %s
+`, cmd.shell))
+}
+
+func (cmd *completionShellCmd) Parse(arguments []string) error {
+	if len(arguments) != 0 {
+		return ErrNArg
+	}
+	return nil
+}
+
+func (cmd *completionShellCmd) Run() error {
+	return cmd.cmds.GenerateCompletion(cmd.shell, os.Stdout)
+}
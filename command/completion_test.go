@@ -0,0 +1,102 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"bytes"
+	"flag"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type completeFlagCmd struct {
+	fakeCmd
+	flags *flag.FlagSet
+}
+
+func (c *completeFlagCmd) Flags() *flag.FlagSet { return c.flags }
+
+func newCompletionTestSet() *CommandSet {
+	var cmds CommandSet
+	cmds.Add(&fakeCmd{name: "list", desc: "List things"})
+	cmds.Add(&fakeCmd{name: "hidden"}) // unlisted: empty Description
+
+	flags := flag.NewFlagSet("add", flag.ContinueOnError)
+	flags.String("tag", "", "tag to apply")
+	cmds.Add(&completeFlagCmd{fakeCmd: fakeCmd{name: "add", desc: "Add a thing"}, flags: flags})
+	return &cmds
+}
+
+func TestCompleteTopLevelCommands(t *testing.T) {
+	cmds := newCompletionTestSet()
+
+	got := cmds.Complete([]string{""})
+	sort.Strings(got)
+	want := []string{"add", "list"} // "hidden" is unlisted and excluded
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("Complete([\"\"]) = %v, want %v", got, want)
+	}
+}
+
+func TestCompletePartialWord(t *testing.T) {
+	cmds := newCompletionTestSet()
+
+	got := cmds.Complete([]string{"li"})
+	want := []string{"list"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf(`Complete(["li"]) = %v, want %v`, got, want)
+	}
+}
+
+func TestCompleteFlagsOfMatchedCommand(t *testing.T) {
+	cmds := newCompletionTestSet()
+
+	got := cmds.Complete([]string{"add", "--ta"})
+	want := []string{"--tag"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf(`Complete(["add", "--ta"]) = %v, want %v`, got, want)
+	}
+}
+
+func TestGenerateCompletionUnsupportedShell(t *testing.T) {
+	var cmds CommandSet
+	var buf bytes.Buffer
+	if err := cmds.GenerateCompletion("csh", &buf); err == nil {
+		t.Fatal("GenerateCompletion(\"csh\", ...) = nil error, want an error")
+	}
+}
+
+func TestGenerateCompletionKnownShells(t *testing.T) {
+	var cmds CommandSet
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var buf bytes.Buffer
+		if err := cmds.GenerateCompletion(shell, &buf); err != nil {
+			t.Errorf("GenerateCompletion(%q, ...) = %v, want nil", shell, err)
+		}
+		if buf.Len() == 0 {
+			t.Errorf("GenerateCompletion(%q, ...) wrote no output", shell)
+		}
+	}
+}
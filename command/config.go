@@ -0,0 +1,177 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/sstallion/go-tools/util"
+)
+
+// BindOptions configures BindConfig.
+type BindOptions struct {
+	// EnvPrefix is prepended to the upper-cased, underscore-separated flag
+	// name to derive the environment variable consulted for each flag (for
+	// example, --foo-bar becomes PREFIX_FOO_BAR). It defaults to the
+	// upper-cased base program name.
+	EnvPrefix string
+
+	// ConfigFlag names the flag used to specify a config file. It defaults
+	// to "config".
+	ConfigFlag string
+}
+
+func (opts BindOptions) configFlagName() string {
+	if opts.ConfigFlag == "" {
+		return "config"
+	}
+	return opts.ConfigFlag
+}
+
+func (opts BindOptions) envPrefix() string {
+	if opts.EnvPrefix == "" {
+		return strings.ToUpper(util.Program())
+	}
+	return opts.EnvPrefix
+}
+
+func (opts BindOptions) envName(flagName string) string {
+	return opts.envPrefix() + "_" + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+var (
+	configMu      sync.Mutex
+	configOpts    = make(map[*flag.FlagSet]BindOptions)
+	configSources = make(map[*flag.FlagSet]map[string]string)
+)
+
+// BindConfig registers a -config (or opts.ConfigFlag) flag on fs and arranges
+// for any flag in fs left unset on the command line to fall back, in order,
+// to an environment variable derived from the flag's name (see
+// BindOptions.EnvPrefix), then to a value read from the file named by
+// -config. Config files must have a ".json" extension and are decoded with
+// encoding/json; other formats are not supported, since a flat key/value
+// reader cannot correctly represent YAML, TOML, or HCL's section and
+// quoting rules without silently misreading values. Values already set on
+// the command line always take precedence.
+func BindConfig(fs *flag.FlagSet, opts BindOptions) {
+	if fs.Lookup(opts.configFlagName()) == nil {
+		fs.String(opts.configFlagName(), "", "Read configuration from `file` (.json)")
+	}
+	configMu.Lock()
+	configOpts[fs] = opts
+	configMu.Unlock()
+}
+
+// resolveConfig applies BindConfig's env-then-config fallback to every flag
+// in fs that was not set on the command line, recording the source used for
+// each resolved flag so PrintResolvedConfig can report it.
+func resolveConfig(fs *flag.FlagSet) error {
+	configMu.Lock()
+	opts, bound := configOpts[fs]
+	configMu.Unlock()
+	if !bound {
+		return nil
+	}
+
+	var fileValues map[string]string
+	if path := fs.Lookup(opts.configFlagName()).Value.String(); path != "" {
+		values, err := loadConfigFile(path)
+		if err != nil {
+			return fmt.Errorf("reading config file %s: %w", path, err)
+		}
+		fileValues = values
+	}
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	sources := make(map[string]string)
+	var setErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		if setErr != nil || set[f.Name] || f.Name == opts.configFlagName() {
+			return
+		}
+		envName := opts.envName(f.Name)
+		if v, ok := os.LookupEnv(envName); ok {
+			if err := fs.Set(f.Name, v); err != nil {
+				setErr = fmt.Errorf("invalid value for %s: %w", envName, err)
+				return
+			}
+			sources[f.Name] = "env:" + envName
+			return
+		}
+		if v, ok := fileValues[f.Name]; ok {
+			if err := fs.Set(f.Name, v); err != nil {
+				setErr = fmt.Errorf("invalid value for -%s: %w", f.Name, err)
+				return
+			}
+			sources[f.Name] = "config:" + fs.Lookup(opts.configFlagName()).Value.String()
+		}
+	})
+	if setErr != nil {
+		return setErr
+	}
+
+	configMu.Lock()
+	configSources[fs] = sources
+	configMu.Unlock()
+	return nil
+}
+
+// loadConfigFile reads and parses the config file at path, choosing the
+// parser by its extension. Only ".json" is recognized; any other extension,
+// including ".yaml", ".toml", and ".hcl", is an error.
+func loadConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		return parseJSONConfig(data)
+	default:
+		return nil, fmt.Errorf("unsupported config file extension: %s", ext)
+	}
+}
+
+// parseJSONConfig decodes a JSON object into a map of flag name to string
+// value.
+func parseJSONConfig(data []byte) (map[string]string, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	values := make(map[string]string, len(raw))
+	for k, v := range raw {
+		values[k] = fmt.Sprint(v)
+	}
+	return values, nil
+}
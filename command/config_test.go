@@ -0,0 +1,119 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveConfigPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"host":"from-file","port":"from-file"}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TESTAPP_PORT", "from-env")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "localhost", "host")
+	fs.String("port", "0", "port")
+	fs.String("name", "default-name", "name")
+	BindConfig(fs, BindOptions{EnvPrefix: "TESTAPP"})
+
+	if err := fs.Parse([]string{"-config", path, "-name", "from-flag"}); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if err := resolveConfig(fs); err != nil {
+		t.Fatalf("resolveConfig() = %v, want nil", err)
+	}
+
+	cases := map[string]string{
+		"name": "from-flag", // flag beats everything
+		"port": "from-env",  // env beats config file
+		"host": "from-file", // file beats default
+	}
+	for name, want := range cases {
+		if got := fs.Lookup(name).Value.String(); got != want {
+			t.Errorf("flag %q = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestResolveConfigSatisfiesRequireFlag guards against a regression where
+// resolveConfig set resolved values via f.Value.Set instead of fs.Set, so a
+// required flag satisfied entirely by an env var or config file was still
+// reported as unset by fs.Visit, the same mechanism resolveRequiredFlags
+// uses to decide whether a required flag was already satisfied.
+func TestResolveConfigSatisfiesRequireFlag(t *testing.T) {
+	t.Setenv("TESTAPP2_HOST", "from-env")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.String("host", "", "host")
+	RequireFlag(fs, "host")
+	BindConfig(fs, BindOptions{EnvPrefix: "TESTAPP2"})
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if err := resolveConfig(fs); err != nil {
+		t.Fatalf("resolveConfig() = %v, want nil", err)
+	}
+	if err := resolveRequiredFlags(fs); err != nil {
+		t.Fatalf("resolveRequiredFlags() = %v, want nil; -host was already satisfied by the environment", err)
+	}
+}
+
+func TestLoadConfigFileUnsupportedExtension(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte("host: example\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadConfigFile(path); err == nil {
+		t.Fatal("loadConfigFile() = nil error, want an error for an unsupported extension")
+	}
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"host":"example","count":5}`), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	values, err := loadConfigFile(path)
+	if err != nil {
+		t.Fatalf("loadConfigFile() = %v, want nil", err)
+	}
+	if values["host"] != "example" {
+		t.Errorf(`values["host"] = %q, want "example"`, values["host"])
+	}
+	if values["count"] != "5" {
+		t.Errorf(`values["count"] = %q, want "5"`, values["count"])
+	}
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+)
+
+// logLevel backs the -v/--log-level global flag registered by Parse. It is
+// consulted by the default logger's handler, so adjusting it at runtime
+// changes the verbosity of subsequent log records.
+var logLevel = new(slog.LevelVar)
+
+// levelFlag adapts logLevel to the flag.Value interface, allowing it to be
+// set from a string such as "DEBUG" or "WARN".
+type levelFlag struct{ v *slog.LevelVar }
+
+func (f levelFlag) String() string {
+	if f.v == nil {
+		return ""
+	}
+	return f.v.Level().String()
+}
+
+func (f levelFlag) Set(s string) error {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return err
+	}
+	f.v.Set(level)
+	return nil
+}
+
+// baseLogger is the *slog.Logger each command's logger is derived from. It
+// may be replaced with SetLogger to plug in a different slog.Handler, such
+// as an adapter that forwards records to a logrus-style backend.
+var baseLogger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: logLevel}))
+
+// SetLogger sets the base *slog.Logger used for command lifecycle logging.
+// It must be called before Parse to take effect.
+func SetLogger(logger *slog.Logger) {
+	baseLogger = logger
+}
+
+// RegisterLogFlags registers the -v/--log-level global flag on fs, binding
+// it to the log level consulted by the default logger's handler. It is
+// called automatically by Parse for the default flag and command sets.
+func RegisterLogFlags(fs *flag.FlagSet) {
+	fs.Var(levelFlag{logLevel}, "log-level", "Set the `log level` (DEBUG, INFO, WARN, or ERROR)")
+	fs.Var(levelFlag{logLevel}, "v", "Shorthand for -log-level")
+}
+
+// ContextRunner is an optional interface implemented by commands that need
+// access to a context.Context while running, typically to honor
+// cancellation or to retrieve the command's logger with LoggerFromContext.
+// If a command implements ContextRunner, RunContext is called in place of
+// Run.
+type ContextRunner interface {
+	RunContext(ctx context.Context) error
+}
+
+type loggerKey struct{}
+
+// LoggerFromContext returns the *slog.Logger carried by ctx. Commands
+// implementing ContextRunner receive a context annotated with the command's
+// name and arguments; calling LoggerFromContext on it returns a logger that
+// includes those fields. If ctx carries no logger, baseLogger is returned.
+func LoggerFromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return baseLogger
+}
+
+// runCommand invokes cmd, preferring RunContext over Run when cmd implements
+// ContextRunner so existing commands that only implement Run continue to
+// work unmodified.
+func runCommand(ctx context.Context, cmd Command) error {
+	if cr, ok := cmd.(ContextRunner); ok {
+		return cr.RunContext(ctx)
+	}
+	return cmd.Run()
+}
@@ -0,0 +1,108 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log/slog"
+	"testing"
+)
+
+func TestRegisterLogFlagsParsesLevel(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	RegisterLogFlags(fs)
+
+	if err := fs.Parse([]string{"-log-level", "DEBUG"}); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if got := logLevel.Level(); got != slog.LevelDebug {
+		t.Fatalf("logLevel = %v, want %v", got, slog.LevelDebug)
+	}
+
+	if err := fs.Parse([]string{"-v", "WARN"}); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if got := logLevel.Level(); got != slog.LevelWarn {
+		t.Fatalf("logLevel = %v, want %v", got, slog.LevelWarn)
+	}
+}
+
+func TestLevelFlagSetInvalid(t *testing.T) {
+	f := levelFlag{new(slog.LevelVar)}
+	if err := f.Set("NOTALEVEL"); err == nil {
+		t.Fatal(`Set("NOTALEVEL") = nil, want an error`)
+	}
+}
+
+func TestLoggerFromContextDefault(t *testing.T) {
+	if got := LoggerFromContext(context.Background()); got != baseLogger {
+		t.Fatalf("LoggerFromContext(Background()) = %v, want baseLogger", got)
+	}
+}
+
+func TestLoggerFromContextAnnotated(t *testing.T) {
+	logger := slog.Default()
+	ctx := context.WithValue(context.Background(), loggerKey{}, logger)
+	if got := LoggerFromContext(ctx); got != logger {
+		t.Fatalf("LoggerFromContext(ctx) = %v, want %v", got, logger)
+	}
+}
+
+type contextRunCmd struct {
+	fakeCmd
+	gotCtx context.Context
+}
+
+func (c *contextRunCmd) RunContext(ctx context.Context) error {
+	c.gotCtx = ctx
+	return nil
+}
+
+func TestRunCommandPrefersRunContext(t *testing.T) {
+	cmd := &contextRunCmd{fakeCmd: fakeCmd{name: "ctx"}}
+	ctx := context.WithValue(context.Background(), loggerKey{}, slog.Default())
+	if err := runCommand(ctx, cmd); err != nil {
+		t.Fatalf("runCommand() = %v, want nil", err)
+	}
+	if cmd.gotCtx != ctx {
+		t.Fatal("runCommand() did not pass ctx through to RunContext")
+	}
+}
+
+func TestRunCommandFallsBackToRun(t *testing.T) {
+	wantErr := errors.New("boom")
+	cmd := &runOnlyCmd{fakeCmd{name: "run-only"}, wantErr}
+	if err := runCommand(context.Background(), cmd); !errors.Is(err, wantErr) {
+		t.Fatalf("runCommand() = %v, want %v", err, wantErr)
+	}
+}
+
+type runOnlyCmd struct {
+	fakeCmd
+	err error
+}
+
+func (c *runOnlyCmd) Run() error { return c.err }
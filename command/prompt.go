@@ -0,0 +1,92 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/sstallion/go-tools/util"
+	"github.com/sstallion/go-tools/util/prompt"
+)
+
+// ui is the prompt.Ui used to resolve flags marked required with
+// RequireFlag. It may be replaced with SetUi, which is primarily useful in
+// tests, where a *prompt.MockUi supplies canned answers.
+var ui prompt.Ui = prompt.NewBasicUi()
+
+// SetUi sets the prompt.Ui used to resolve required flags left unset on the
+// command line.
+func SetUi(u prompt.Ui) {
+	ui = u
+}
+
+var (
+	requiredMu    sync.Mutex
+	requiredFlags = make(map[*flag.FlagSet][]string)
+)
+
+// RequireFlag marks the flag named name in fs as required. If the flag was
+// set neither on the command line nor, for a flag set bound with BindConfig,
+// from an environment variable or config file once fs has been parsed,
+// CommandSet prompts for a value on a TTY using the Ui set with SetUi, or
+// fails with an ErrNArg-wrapping error otherwise.
+func RequireFlag(fs *flag.FlagSet, name string) {
+	requiredMu.Lock()
+	defer requiredMu.Unlock()
+	requiredFlags[fs] = append(requiredFlags[fs], name)
+}
+
+// resolveRequiredFlags prompts for, or fails on, any flag in fs marked
+// required with RequireFlag that was not explicitly set once fs has been
+// parsed. A flag is considered set if it was passed on the command line,
+// even if the value given matches the flag's default.
+func resolveRequiredFlags(fs *flag.FlagSet) error {
+	requiredMu.Lock()
+	names := requiredFlags[fs]
+	requiredMu.Unlock()
+
+	set := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for _, name := range names {
+		f := fs.Lookup(name)
+		if f == nil || set[name] {
+			continue
+		}
+		if !util.IsTerminal(os.Stdin) {
+			return fmt.Errorf("%w: missing required flag: -%s", ErrNArg, name)
+		}
+		answer, err := ui.Ask(fmt.Sprintf("%s: ", name))
+		if err != nil {
+			return err
+		}
+		if err := f.Value.Set(answer); err != nil {
+			return fmt.Errorf("invalid value for -%s: %w", name, err)
+		}
+	}
+	return nil
+}
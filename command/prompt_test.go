@@ -0,0 +1,76 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"testing"
+)
+
+// TestResolveRequiredFlagsExplicitZeroValue guards against a regression
+// where a required flag explicitly set to its zero value (e.g. -count 0,
+// default 0) was mistaken for an unset flag because resolveRequiredFlags
+// compared against DefValue instead of tracking explicit-set state.
+func TestResolveRequiredFlagsExplicitZeroValue(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("count", 0, "item count")
+	RequireFlag(fs, "count")
+
+	if err := fs.Parse([]string{"-count", "0"}); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if err := resolveRequiredFlags(fs); err != nil {
+		t.Fatalf("resolveRequiredFlags() = %v, want nil", err)
+	}
+}
+
+// TestResolveRequiredFlagsUnsetFailsWithoutTTY asserts that a required flag
+// left unset errors out rather than prompting when standard input is not a
+// terminal. util.IsTerminal treats any character device as a terminal, so
+// stdin is swapped for a regular file to get a deterministic non-terminal
+// result regardless of how the test runner itself was invoked.
+func TestResolveRequiredFlagsUnsetFailsWithoutTTY(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "stdin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	oldStdin := os.Stdin
+	os.Stdin = f
+	defer func() { os.Stdin = oldStdin }()
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Int("count", 0, "item count")
+	RequireFlag(fs, "count")
+
+	if err := fs.Parse(nil); err != nil {
+		t.Fatalf("Parse() = %v, want nil", err)
+	}
+	if err := resolveRequiredFlags(fs); !errors.Is(err, ErrNArg) {
+		t.Fatalf("resolveRequiredFlags() = %v, want an ErrNArg-wrapping error", err)
+	}
+}
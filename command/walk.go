@@ -0,0 +1,123 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+
+	"github.com/sstallion/go-tools/util"
+)
+
+// dumpCommandName is the hidden command used by external tools, such as
+// cmd/doxxer, to request a machine-readable description of a command tree
+// from a running binary. It is handled directly by CommandSet.Parse and
+// never shown in usage output.
+const dumpCommandName = "__dump"
+
+// Walk calls fn for every command in the default command set, and
+// recursively for every command in any nested CommandSet owned by a command
+// implementing Parent. See CommandSet.Walk for details.
+func Walk(fn func(path []string, cmd Command)) {
+	CommandLine.Walk(fn)
+}
+
+// Walk calls fn for every command in cmds, and recursively for every command
+// in any nested CommandSet owned by a command implementing Parent. fn is
+// called with the full path of command names leading to each command,
+// allowing callers to reconstruct the command tree without needing to know
+// its shape ahead of time.
+func (cmds *CommandSet) Walk(fn func(path []string, cmd Command)) {
+	walk(cmds, nil, fn)
+}
+
+func walk(cmds *CommandSet, prefix []string, fn func(path []string, cmd Command)) {
+	cmds.Visit(func(cmd Command) {
+		path := make([]string, len(prefix)+1)
+		copy(path, prefix)
+		path[len(prefix)] = cmd.Name()
+		fn(path, cmd)
+		if parent, ok := cmd.(Parent); ok {
+			walk(parent.Commands(), path, fn)
+		}
+	})
+}
+
+// FlagInfo describes a single flag in a structured form, suitable for
+// rendering documentation without parsing flag.PrintDefaults output.
+type FlagInfo struct {
+	Name    string
+	Usage   string
+	Default string
+}
+
+// FlagsInfo returns structured information about every flag registered on
+// cmd's flag set, in flag.FlagSet.VisitAll order. It returns nil if cmd does
+// not implement Flagger.
+func FlagsInfo(cmd Command) []FlagInfo {
+	flagger, ok := cmd.(Flagger)
+	if !ok {
+		return nil
+	}
+	var infos []FlagInfo
+	flagger.Flags().VisitAll(func(f *flag.Flag) {
+		infos = append(infos, FlagInfo{Name: f.Name, Usage: f.Usage, Default: f.DefValue})
+	})
+	return infos
+}
+
+// DumpNode describes a single command in a command tree, as reported by the
+// hidden "__dump" command.
+type DumpNode struct {
+	Path        []string   `json:"path"`
+	Description string     `json:"description"`
+	Flags       []FlagInfo `json:"flags"`
+}
+
+// Dump describes an entire command tree, as reported by the hidden "__dump"
+// command.
+type Dump struct {
+	Program  string     `json:"program"`
+	Commands []DumpNode `json:"commands"`
+}
+
+// writeDump JSON-encodes a Dump of cmds to w, for consumption by external
+// tools such as cmd/doxxer. Unlisted commands (those with an empty
+// Description, see writeCommands) are omitted, the same way they are
+// omitted from usage output; their children, if any, are still visited.
+func (cmds *CommandSet) writeDump(w io.Writer) error {
+	dump := Dump{Program: util.Program()}
+	cmds.Walk(func(path []string, cmd Command) {
+		if cmd.Description() == "" {
+			return
+		}
+		dump.Commands = append(dump.Commands, DumpNode{
+			Path:        path,
+			Description: cmd.Description(),
+			Flags:       FlagsInfo(cmd),
+		})
+	})
+	return json.NewEncoder(w).Encode(dump)
+}
@@ -0,0 +1,82 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package command
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"reflect"
+	"testing"
+)
+
+type parentCmd struct {
+	fakeCmd
+	sub CommandSet
+}
+
+func (c *parentCmd) Flags() *flag.FlagSet  { return flag.NewFlagSet(c.name, flag.ContinueOnError) }
+func (c *parentCmd) Commands() *CommandSet { return &c.sub }
+
+func TestWalkVisitsNestedCommands(t *testing.T) {
+	var cmds CommandSet
+	parent := &parentCmd{fakeCmd: fakeCmd{name: "remote", desc: "Manage remotes"}}
+	parent.sub.Add(&fakeCmd{name: "add", desc: "Add a remote"})
+	cmds.Add(parent)
+	cmds.Add(&fakeCmd{name: "init", desc: "Initialize"})
+
+	var paths [][]string
+	cmds.Walk(func(path []string, cmd Command) {
+		paths = append(paths, append([]string(nil), path...))
+	})
+
+	want := [][]string{{"remote"}, {"remote", "add"}, {"init"}}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("Walk paths = %v, want %v", paths, want)
+	}
+}
+
+func TestWriteDumpOmitsUnlistedCommands(t *testing.T) {
+	var cmds CommandSet
+	cmds.Add(&fakeCmd{name: "visible", desc: "A visible command"})
+	cmds.Add(&fakeCmd{name: "hidden"}) // unlisted: empty Description
+
+	var buf bytes.Buffer
+	if err := cmds.writeDump(&buf); err != nil {
+		t.Fatalf("writeDump() = %v, want nil", err)
+	}
+
+	var dump Dump
+	if err := json.Unmarshal(buf.Bytes(), &dump); err != nil {
+		t.Fatalf("decoding dump: %v", err)
+	}
+
+	var names []string
+	for _, node := range dump.Commands {
+		names = append(names, node.Path[len(node.Path)-1])
+	}
+	if want := []string{"visible"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("dumped commands = %v, want %v", names, want)
+	}
+}
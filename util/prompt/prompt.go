@@ -0,0 +1,209 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+// Package prompt provides a small interactive terminal UI, used by command
+// to ask the user for flag values that were required but left unset.
+package prompt
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Ui is implemented by types that can ask the user questions on a terminal.
+type Ui interface {
+	// Ask prompts for a line of free-form text.
+	Ask(prompt string) (string, error)
+
+	// AskSecret prompts for a line of text without echoing it back.
+	AskSecret(prompt string) (string, error)
+
+	// Confirm prompts for a yes/no answer.
+	Confirm(prompt string) (bool, error)
+
+	// Select prompts for a choice among options, returning its index.
+	Select(prompt string, options []string) (int, error)
+}
+
+// BasicUi is a Ui that reads from Reader, defaulting to os.Stdin, and writes
+// to Writer, defaulting to os.Stderr.
+type BasicUi struct {
+	Reader io.Reader
+	Writer io.Writer
+
+	once sync.Once
+	in   *bufio.Reader
+}
+
+// NewBasicUi returns a BasicUi that reads from os.Stdin and writes to
+// os.Stderr.
+func NewBasicUi() *BasicUi {
+	return &BasicUi{Reader: os.Stdin, Writer: os.Stderr}
+}
+
+func (ui *BasicUi) reader() *bufio.Reader {
+	ui.once.Do(func() {
+		r := ui.Reader
+		if r == nil {
+			r = os.Stdin
+		}
+		ui.in = bufio.NewReader(r)
+	})
+	return ui.in
+}
+
+func (ui *BasicUi) writer() io.Writer {
+	if ui.Writer == nil {
+		return os.Stderr
+	}
+	return ui.Writer
+}
+
+func (ui *BasicUi) readLine(prompt string) (string, error) {
+	fmt.Fprint(ui.writer(), prompt)
+	line, err := ui.reader().ReadString('\n')
+	return strings.TrimRight(line, "\r\n"), err
+}
+
+// Ask implements Ui.
+func (ui *BasicUi) Ask(prompt string) (string, error) {
+	return ui.readLine(prompt)
+}
+
+// AskSecret implements Ui. Echo is disabled on a best-effort basis using
+// "stty"; if that fails, the answer is read without suppressing echo.
+func (ui *BasicUi) AskSecret(prompt string) (string, error) {
+	disableEcho()
+	defer enableEcho()
+	defer fmt.Fprintln(ui.writer())
+	return ui.readLine(prompt)
+}
+
+// Confirm implements Ui.
+func (ui *BasicUi) Confirm(prompt string) (bool, error) {
+	for {
+		answer, err := ui.readLine(prompt + " [y/n]: ")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		fmt.Fprintln(ui.writer(), "Please answer y or n.")
+	}
+}
+
+// Select implements Ui.
+func (ui *BasicUi) Select(prompt string, options []string) (int, error) {
+	fmt.Fprintln(ui.writer(), prompt)
+	for i, option := range options {
+		fmt.Fprintf(ui.writer(), "  %d) %s\n", i+1, option)
+	}
+	for {
+		answer, err := ui.readLine("Enter a number: ")
+		if err != nil {
+			return 0, err
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(answer)); err == nil && n >= 1 && n <= len(options) {
+			return n - 1, nil
+		}
+		fmt.Fprintf(ui.writer(), "Please enter a number between 1 and %d.\n", len(options))
+	}
+}
+
+// disableEcho and enableEcho toggle terminal echo for the controlling
+// terminal using "stty", which is assumed to be available in $PATH. Errors
+// are ignored; in the worst case, a secret is echoed to the terminal.
+func disableEcho() {
+	stty("-echo")
+}
+
+func enableEcho() {
+	stty("echo")
+}
+
+func stty(args ...string) {
+	cmd := exec.Command("stty", append([]string{"-F", "/dev/tty"}, args...)...)
+	cmd.Run()
+}
+
+// MockUi is a Ui intended for use in tests. Each field holds the canned
+// answers returned for successive calls to the corresponding method; once
+// exhausted, the method returns io.EOF.
+type MockUi struct {
+	AskAnswers       []string
+	AskSecretAnswers []string
+	ConfirmAnswers   []bool
+	SelectAnswers    []int
+
+	askN, secretN, confirmN, selectN int
+}
+
+// Ask implements Ui.
+func (ui *MockUi) Ask(prompt string) (string, error) {
+	if ui.askN >= len(ui.AskAnswers) {
+		return "", io.EOF
+	}
+	answer := ui.AskAnswers[ui.askN]
+	ui.askN++
+	return answer, nil
+}
+
+// AskSecret implements Ui.
+func (ui *MockUi) AskSecret(prompt string) (string, error) {
+	if ui.secretN >= len(ui.AskSecretAnswers) {
+		return "", io.EOF
+	}
+	answer := ui.AskSecretAnswers[ui.secretN]
+	ui.secretN++
+	return answer, nil
+}
+
+// Confirm implements Ui.
+func (ui *MockUi) Confirm(prompt string) (bool, error) {
+	if ui.confirmN >= len(ui.ConfirmAnswers) {
+		return false, io.EOF
+	}
+	answer := ui.ConfirmAnswers[ui.confirmN]
+	ui.confirmN++
+	return answer, nil
+}
+
+// Select implements Ui.
+func (ui *MockUi) Select(prompt string, options []string) (int, error) {
+	if ui.selectN >= len(ui.SelectAnswers) {
+		return 0, io.EOF
+	}
+	answer := ui.SelectAnswers[ui.selectN]
+	ui.selectN++
+	return answer, nil
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2023 Steven Stallion <sstallion@gmail.com>
+//
+// Redistribution and use in source and binary forms, with or without
+// modification, are permitted provided that the following conditions
+// are met:
+// 1. Redistributions of source code must retain the above copyright
+//    notice, this list of conditions and the following disclaimer.
+// 2. Redistributions in binary form must reproduce the above copyright
+//    notice, this list of conditions and the following disclaimer in the
+//    documentation and/or other materials provided with the distribution.
+//
+// THIS SOFTWARE IS PROVIDED BY THE AUTHOR AND CONTRIBUTORS "AS IS" AND
+// ANY EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE
+// IMPLIED WARRANTIES OF MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE
+// ARE DISCLAIMED.  IN NO EVENT SHALL THE AUTHOR OR CONTRIBUTORS BE LIABLE
+// FOR ANY DIRECT, INDIRECT, INCIDENTAL, SPECIAL, EXEMPLARY, OR CONSEQUENTIAL
+// DAMAGES (INCLUDING, BUT NOT LIMITED TO, PROCUREMENT OF SUBSTITUTE GOODS
+// OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS INTERRUPTION)
+// HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT, STRICT
+// LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY
+// OUT OF THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF
+// SUCH DAMAGE.
+
+package prompt
+
+import (
+	"io"
+	"testing"
+)
+
+func TestMockUiAsk(t *testing.T) {
+	ui := &MockUi{AskAnswers: []string{"first", "second"}}
+
+	for _, want := range []string{"first", "second"} {
+		got, err := ui.Ask("prompt: ")
+		if err != nil {
+			t.Fatalf("Ask() = %v, want nil", err)
+		}
+		if got != want {
+			t.Fatalf("Ask() = %q, want %q", got, want)
+		}
+	}
+
+	if _, err := ui.Ask("prompt: "); err != io.EOF {
+		t.Fatalf("Ask() after answers exhausted = %v, want io.EOF", err)
+	}
+}
+
+func TestMockUiConfirm(t *testing.T) {
+	ui := &MockUi{ConfirmAnswers: []bool{true, false}}
+
+	for _, want := range []bool{true, false} {
+		got, err := ui.Confirm("prompt")
+		if err != nil {
+			t.Fatalf("Confirm() = %v, want nil", err)
+		}
+		if got != want {
+			t.Fatalf("Confirm() = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestMockUiSelect(t *testing.T) {
+	ui := &MockUi{SelectAnswers: []int{1}}
+
+	got, err := ui.Select("prompt", []string{"a", "b", "c"})
+	if err != nil {
+		t.Fatalf("Select() = %v, want nil", err)
+	}
+	if got != 1 {
+		t.Fatalf("Select() = %d, want 1", got)
+	}
+}
@@ -23,7 +23,10 @@
 
 package util
 
-import "runtime/debug"
+import (
+	"os"
+	"runtime/debug"
+)
 
 // BuildVersion returns the module version in the build information read from
 // the Go binary.
@@ -49,3 +52,12 @@ func FixVersion(version *string) {
 		*version = BuildVersion()
 	}
 }
+
+// IsTerminal reports whether f appears to be connected to a terminal.
+func IsTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}